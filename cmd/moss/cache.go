@@ -0,0 +1,167 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/gofrs/flock"
+	"github.com/rs/zerolog/log"
+)
+
+// repoCache manages a persistent bare mirror per repo under a shared cache
+// root, so repeat runs can `git remote update` instead of re-cloning.
+type repoCache struct {
+	root    string
+	shallow bool
+	depth   int
+}
+
+func newRepoCache(conf *Conf) *repoCache {
+	return &repoCache{
+		root:    conf.cacheDir(),
+		shallow: conf.Cache.Shallow,
+		depth:   conf.Cache.ShallowDepth,
+	}
+}
+
+// bareDir returns the path of the bare mirror for org/repo, creating the
+// cache root if needed.
+func (c *repoCache) bareDir(org, repo string) (string, error) {
+	if err := os.MkdirAll(c.root, 0755); err != nil {
+		return "", err
+	}
+	return filepath.Join(c.root, org, repo+".git"), nil
+}
+
+// lockPath returns the per-repo lock file path used to keep concurrent runs
+// from stomping on the same bare mirror.
+func (c *repoCache) lockPath(bare string) string {
+	return bare + ".lock"
+}
+
+// sync ensures a bare mirror of cloneUrl exists at the cached path and is
+// up to date, cloning it for the first time if necessary. It returns the
+// path to the bare mirror. Callers must hold the lock returned alongside it
+// until they are done with the worktree/scan.
+func (c *repoCache) sync(ctx context.Context, org, repo, cloneUrl string) (bare string, unlock func(), err error) {
+	bare, err = c.bareDir(org, repo)
+	if err != nil {
+		return "", nil, err
+	}
+	lock := flock.New(c.lockPath(bare))
+	if err := lock.Lock(); err != nil {
+		return "", nil, fmt.Errorf("locking cache entry for %s/%s: %w", org, repo, err)
+	}
+	unlock = func() {
+		if err := lock.Unlock(); err != nil {
+			log.Warn().Err(err).Str("repo", repo).Msg("failed to release cache lock")
+		}
+	}
+
+	if _, statErr := os.Stat(bare); os.IsNotExist(statErr) {
+		if err := c.clone(ctx, cloneUrl, bare); err != nil {
+			unlock()
+			return "", nil, err
+		}
+		return bare, unlock, nil
+	}
+
+	if err := c.update(ctx, bare); err != nil {
+		unlock()
+		return "", nil, err
+	}
+	return bare, unlock, nil
+}
+
+// clone creates a new bare mirror, optionally using a shallow/partial clone
+// for the first fetch.
+func (c *repoCache) clone(ctx context.Context, cloneUrl, bare string) error {
+	if err := os.MkdirAll(filepath.Dir(bare), 0755); err != nil {
+		return err
+	}
+	args := []string{"clone", "--mirror"}
+	if c.shallow {
+		args = append(args, "--filter=blob:none")
+		if c.depth > 0 {
+			args = append(args, fmt.Sprintf("--depth=%d", c.depth))
+		}
+	}
+	args = append(args, cloneUrl, bare)
+	return runGit(ctx, args...)
+}
+
+// update fetches new refs into an existing bare mirror, pruning any that
+// were deleted upstream.
+func (c *repoCache) update(ctx context.Context, bare string) error {
+	cmd := exec.CommandContext(ctx, "git", "--git-dir="+bare, "remote", "update", "--prune")
+	var errb bytes.Buffer
+	cmd.Stderr = &errb
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("git remote update in %s: %w: %s", bare, err, strings.TrimSpace(errb.String()))
+	}
+	return nil
+}
+
+// worktree materializes a disposable worktree off the bare mirror for tools
+// (like gitleaks) that need a real checkout rather than a bare repo.
+func (c *repoCache) worktree(ctx context.Context, bare, dir string) error {
+	return runGit(ctx, "--git-dir="+bare, "worktree", "add", "--detach", dir)
+}
+
+// removeWorktree prunes a worktree created by worktree() once the scan is done.
+func (c *repoCache) removeWorktree(ctx context.Context, bare, dir string) {
+	if err := runGit(ctx, "--git-dir="+bare, "worktree", "remove", "--force", dir); err != nil {
+		log.Warn().Err(err).Str("dir", dir).Msg("failed to remove scan worktree")
+	}
+}
+
+// gc prunes cache entries for repos that no longer appear in liveRepos
+// (e.g. because they were removed or archived upstream).
+func (c *repoCache) gc(org string, liveRepos map[string]bool) error {
+	orgDir := filepath.Join(c.root, org)
+	entries, err := os.ReadDir(orgDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	for _, entry := range entries {
+		// ReadDir also returns each repo's "<repo>.git.lock" file
+		// alongside its "<repo>.git" dir; only the latter is a prunable
+		// repo entry. Lock files are removed once their .git dir is gone,
+		// below, not matched against liveRepos directly - deleting a lock
+		// file out from under a process still holding it defeats flock
+		// (the next locker just creates a new inode and acquires it).
+		if !strings.HasSuffix(entry.Name(), ".git") {
+			continue
+		}
+		name := strings.TrimSuffix(entry.Name(), ".git")
+		if liveRepos[name] {
+			continue
+		}
+		path := filepath.Join(orgDir, entry.Name())
+		log.Info().Str("org", org).Str("repo", name).Msg("pruning cache entry for removed/archived repo")
+		if err := os.RemoveAll(path); err != nil {
+			log.Error().Err(err).Str("path", path).Msg("failed to prune cache entry")
+			continue
+		}
+		os.Remove(c.lockPath(path))
+	}
+	return nil
+}
+
+func runGit(ctx context.Context, args ...string) error {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	var errb bytes.Buffer
+	cmd.Stderr = &errb
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("git %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(errb.String()))
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"os"
+
+	"github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v3"
+)
+
+// GithubConfig holds the settings that control which orgs/repos get scanned.
+type GithubConfig struct {
+	OrgsToScan []string `yaml:"orgs_to_scan"`
+}
+
+// OutputConfig controls how findings are written out.
+type OutputConfig struct {
+	Format string       `yaml:"format"`
+	Sinks  []SinkConfig `yaml:"sinks"`
+}
+
+// CacheConfig controls the persistent bare-repo mirror cache used by
+// scan_repo so repeat scans don't re-clone every repo from scratch.
+type CacheConfig struct {
+	// Dir is the root directory holding one bare mirror per repo.
+	// Defaults to "./.moss-cache" when empty.
+	Dir string `yaml:"dir"`
+	// Shallow, when true, uses `git clone --filter=blob:none` for the
+	// first-time clone of a repo instead of a full clone.
+	Shallow bool `yaml:"shallow"`
+	// ShallowDepth, if set, passes --depth=N alongside --filter=blob:none.
+	// Ignored when zero (a blobless clone with full history is used).
+	ShallowDepth int `yaml:"shallow_depth"`
+}
+
+// ScanConfig controls how scanning work is parallelized and which
+// scanner backend(s) are used.
+type ScanConfig struct {
+	// MaxConcurrent caps how many repos are cloned/scanned at once.
+	// Defaults to 4 when unset.
+	MaxConcurrent int `yaml:"max_concurrent"`
+	// Backends lists the scanner backends run against every org by
+	// default ("gitleaks", "trufflehog"). Defaults to ["gitleaks"].
+	Backends []string `yaml:"backends"`
+	// OrgBackends overrides Backends for specific orgs.
+	OrgBackends map[string][]string `yaml:"org_backends"`
+	// DaysToScan is the default lookback window, in days, used for every
+	// org/group/host regardless of which RepoSource backs it. Defaults to
+	// 7 when unset. GitLab/Gerrit instances may override it individually
+	// via their own `days_to_scan` field.
+	DaysToScan int `yaml:"days_to_scan"`
+}
+
+// backendsFor returns the scanner backends configured for org, falling
+// back to the default Backends list, and finally to gitleaks alone.
+func (c *Conf) backendsFor(org string) []string {
+	if b, ok := c.Scan.OrgBackends[org]; ok && len(b) > 0 {
+		return b
+	}
+	if len(c.Scan.Backends) > 0 {
+		return c.Scan.Backends
+	}
+	return []string{"gitleaks"}
+}
+
+// daysToScanFor returns the lookback window, in days, to pass as `since` to
+// org's RepoSource. A GitLab/Gerrit instance's own days_to_scan takes
+// precedence over the shared Scan.DaysToScan default, so one deployment can
+// give different hosts different windows.
+func (c *Conf) daysToScanFor(org string) int {
+	if gl, ok := c.GitlabConfig.instance(org); ok && gl.DaysToScan > 0 {
+		return gl.DaysToScan
+	}
+	if gr, ok := c.GerritConfig.instance(org); ok && gr.DaysToScan > 0 {
+		return gr.DaysToScan
+	}
+	if c.Scan.DaysToScan > 0 {
+		return c.Scan.DaysToScan
+	}
+	return 7
+}
+
+// GitlabInstance configures one GitLab group/subgroup to scan.
+type GitlabInstance struct {
+	// BaseURL is the GitLab instance to talk to, e.g. "https://gitlab.com".
+	BaseURL string `yaml:"base_url"`
+	// TokenEnv names the env var holding the access token for this instance.
+	TokenEnv string `yaml:"token_env"`
+	// Group is the top-level group or subgroup path to enumerate.
+	Group string `yaml:"group"`
+	// DaysToScan overrides Scan.DaysToScan for this instance. Zero means
+	// "use the shared default".
+	DaysToScan int `yaml:"days_to_scan"`
+}
+
+// GitlabConfig holds the GitLab groups moss should scan, keyed by the same
+// org name used elsewhere in Conf (e.g. in OrgBackends).
+type GitlabConfig struct {
+	Instances map[string]GitlabInstance `yaml:"instances"`
+}
+
+func (c GitlabConfig) instance(org string) (GitlabInstance, bool) {
+	gl, ok := c.Instances[org]
+	return gl, ok
+}
+
+// GerritInstance configures one Gerrit host to scan.
+type GerritInstance struct {
+	// BaseURL is the Gerrit instance to talk to, e.g. "https://gerrit.example.com".
+	BaseURL string `yaml:"base_url"`
+	// TokenEnv names the env var holding the HTTP password for this instance.
+	TokenEnv string `yaml:"token_env"`
+	// DaysToScan overrides Scan.DaysToScan for this instance. Zero means
+	// "use the shared default". Gerrit's project list has no per-project
+	// pushed-at timestamp to filter by, so gerritSource.ListRepos ignores
+	// this in practice, but it's still accepted here for consistency.
+	DaysToScan int `yaml:"days_to_scan"`
+}
+
+// GerritConfig holds the Gerrit hosts moss should scan, keyed the same way
+// as GitlabConfig.
+type GerritConfig struct {
+	Instances map[string]GerritInstance `yaml:"instances"`
+}
+
+func (c GerritConfig) instance(org string) (GerritInstance, bool) {
+	gr, ok := c.Instances[org]
+	return gr, ok
+}
+
+// VerifyConfig gates the optional secret-liveness verification pass,
+// which is off by default because it makes live network calls against the
+// services a secret appears to belong to.
+type VerifyConfig struct {
+	Enabled bool `yaml:"enabled"`
+}
+
+// Conf is the top level configuration loaded from MOSS_CONFDIR.
+type Conf struct {
+	GithubConfig GithubConfig `yaml:"github"`
+	GitlabConfig GitlabConfig `yaml:"gitlab"`
+	GerritConfig GerritConfig `yaml:"gerrit"`
+	Output       OutputConfig `yaml:"output"`
+	Cache        CacheConfig  `yaml:"cache"`
+	Scan         ScanConfig   `yaml:"scan"`
+	Verify       VerifyConfig `yaml:"verify"`
+}
+
+// maxConcurrent returns the configured worker pool size, falling back to a
+// sane default.
+func (c *Conf) maxConcurrent() int {
+	if c.Scan.MaxConcurrent > 0 {
+		return c.Scan.MaxConcurrent
+	}
+	return 4
+}
+
+// getConfig reads and parses the YAML config file at path into c.
+func (c *Conf) getConfig(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		log.Fatal().Err(err).Str("path", path).Msg("failed to read config file")
+		return err
+	}
+	if err := yaml.Unmarshal(data, c); err != nil {
+		log.Fatal().Err(err).Str("path", path).Msg("failed to parse config file")
+		return err
+	}
+	return nil
+}
+
+// cacheDir returns the configured cache root, falling back to a sane default.
+func (c *Conf) cacheDir() string {
+	if c.Cache.Dir != "" {
+		return c.Cache.Dir
+	}
+	return "./.moss-cache"
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/gofrs/flock"
+)
+
+// findingStore remembers which finding fingerprints have already been
+// reported to sinks for a given org/repo, so a re-detected secret (e.g.
+// because gitleaks rescanned history it had already scanned) doesn't spam
+// Slack/webhooks/issues every run.
+type findingStore struct {
+	path string
+	mu   sync.Mutex
+	data map[string]map[string]bool // "org/repo" -> set of fingerprints
+}
+
+func loadFindingStore(dir string) (*findingStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	s := &findingStore{path: filepath.Join(dir, "findings_seen.json")}
+	data, err := readFindingStoreFile(s.path)
+	if err != nil {
+		return nil, err
+	}
+	s.data = data
+	return s, nil
+}
+
+// readFindingStoreFile reads the fingerprint sets from path, returning an
+// empty map if the file doesn't exist yet or is empty.
+func readFindingStoreFile(path string) (map[string]map[string]bool, error) {
+	data := make(map[string]map[string]bool)
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return data, nil
+		}
+		return nil, err
+	}
+	if len(raw) == 0 {
+		return data, nil
+	}
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func findingFingerprint(f Finding) string {
+	return f.File + ":" + f.Commit + ":" + f.SecretHash
+}
+
+// newFindings filters findings down to the ones not already recorded as
+// seen for org/repo, without marking them seen yet.
+func (s *findingStore) newFindings(org, repo string, findings []Finding) []Finding {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := org + "/" + repo
+	seen := s.data[key]
+	fresh := make([]Finding, 0, len(findings))
+	for _, f := range findings {
+		if seen != nil && seen[findingFingerprint(f)] {
+			continue
+		}
+		fresh = append(fresh, f)
+	}
+	return fresh
+}
+
+// markSeen records findings as reported for org/repo and persists the
+// store to disk. It reads the current on-disk contents while holding the
+// lock and merges into them rather than overwriting with this process's
+// in-memory copy, so two concurrent `moss` invocations scanning different
+// repos don't stomp on each other's updates (same hazard as scanState.setLastSHA).
+func (s *findingStore) markSeen(org, repo string, findings []Finding) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := org + "/" + repo
+	if s.data[key] == nil {
+		s.data[key] = make(map[string]bool)
+	}
+	for _, f := range findings {
+		s.data[key][findingFingerprint(f)] = true
+	}
+
+	lock := flock.New(s.path + ".lock")
+	if err := lock.Lock(); err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	onDisk, err := readFindingStoreFile(s.path)
+	if err != nil {
+		return err
+	}
+	for k, fingerprints := range s.data {
+		if onDisk[k] == nil {
+			onDisk[k] = make(map[string]bool)
+		}
+		for fp, v := range fingerprints {
+			onDisk[k][fp] = v
+		}
+	}
+	s.data = onDisk
+
+	raw, err := json.MarshalIndent(s.data, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, raw, 0644)
+}
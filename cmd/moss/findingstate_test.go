@@ -0,0 +1,40 @@
+package main
+
+import "testing"
+
+// TestMarkSeenMergesConcurrentWrites simulates two findingStore handles
+// both holding stale in-memory state and marking findings seen for
+// different repos without re-reading the store file in between.
+func TestMarkSeenMergesConcurrentWrites(t *testing.T) {
+	dir := t.TempDir()
+
+	a, err := loadFindingStore(dir)
+	if err != nil {
+		t.Fatalf("loadFindingStore a: %v", err)
+	}
+	b, err := loadFindingStore(dir)
+	if err != nil {
+		t.Fatalf("loadFindingStore b: %v", err)
+	}
+
+	fa := Finding{File: "a.go", Commit: "sha-a", SecretHash: "hash-a"}
+	fb := Finding{File: "b.go", Commit: "sha-b", SecretHash: "hash-b"}
+
+	if err := a.markSeen("org", "repo-a", []Finding{fa}); err != nil {
+		t.Fatalf("markSeen a: %v", err)
+	}
+	if err := b.markSeen("org", "repo-b", []Finding{fb}); err != nil {
+		t.Fatalf("markSeen b: %v", err)
+	}
+
+	final, err := loadFindingStore(dir)
+	if err != nil {
+		t.Fatalf("loadFindingStore final: %v", err)
+	}
+	if got := final.newFindings("org", "repo-a", []Finding{fa}); len(got) != 0 {
+		t.Errorf("repo-a: expected fa to already be seen, got %v", got)
+	}
+	if got := final.newFindings("org", "repo-b", []Finding{fb}); len(got) != 0 {
+		t.Errorf("repo-b: expected fb to already be seen, got %v", got)
+	}
+}
@@ -1,13 +1,13 @@
 package main
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
+	"os/signal"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/google/go-github/v47/github"
@@ -25,120 +25,90 @@ func check_gitleaks_conf(gitleaks_path string) error {
 	return nil
 }
 
-func scan_repo(repo *github.Repository, pat, orgname, gl_conf_path string, results chan GitleaksRepoResult) {
+func scan_repo(ctx context.Context, cache *repoCache, state *scanState, full bool, scanners []Scanner, repo Repo, orgname string, results chan GitleaksRepoResult) {
 	// build a result object
 	result := GitleaksRepoResult{
-		Repository: *repo.Name,
-		URL:        *repo.URL,
-		IsPrivate:  *repo.Private,
+		Repository: repo.Name,
+		URL:        repo.CloneURL,
+		IsPrivate:  repo.Private,
 		Org:        orgname,
 	}
-	// make temp dir
+	// make temp dir for the disposable worktree
 	dir, err := os.MkdirTemp(os.TempDir(), "moss_")
 	if err != nil {
-		log.Error().Err(err).Str("repo", *repo.Name).Msg("failed to create temp dir to scan repo")
+		log.Error().Err(err).Str("repo", repo.Name).Msg("failed to create temp dir to scan repo")
 		result.Err = err
 		results <- result
 		return
 	}
-	log.Debug().Str("repo", *repo.Name).Str("dir", dir).Msg("tempdir set")
+	log.Debug().Str("repo", repo.Name).Str("dir", dir).Msg("tempdir set")
 	//defer os.RemoveAll(dir)
-	// clone into it
-	cloneUrl := *repo.CloneURL
-	cloneUrl = strings.Replace(cloneUrl, "https://", fmt.Sprintf("https://%s@", pat), 1)
-	cloneargs := []string{"clone", cloneUrl, dir}
-	cmd := exec.Command("git", cloneargs...)
-	if err := cmd.Run(); err != nil {
-		log.Error().Err(err).Str("repo", *repo.Name).Msg("failed to clone repo")
-		result.Err = err
-		results <- result
-		return
-	}
-	// run gitleaks
-	outputpath := fmt.Sprintf("%s/__gitleaks.json", dir)
-	outputarg := fmt.Sprintf("-r=%s", outputpath)
-	confpath := fmt.Sprintf("-c=%s", gl_conf_path)
-	// not exactly sure why gitleaks doesn't detect that
-	// it IS a git repo, but we can still detect secrets
-	gitleaks_args := []string{"detect", "-v", "--no-git", "-f=json", "--exit-code=0", outputarg, confpath, dir}
-	// TEMP
-	var outb, errb bytes.Buffer
-	gl_cmd := exec.Command("gitleaks", gitleaks_args...)
-	gl_cmd.Stdout = &outb
-	gl_cmd.Stderr = &errb
-	//fmt.Println(strings.Join(gl_cmd.Args, " "))
-	if err := gl_cmd.Run(); err != nil {
-		log.Error().Err(err).Str("repo", *repo.Name).Msg("error running gitleaks on the repo")
-		result.Err = err
-		results <- result
-		return
-	}
-
-	// code useful for debugging, but not for leaving compiled
-	// fmt.Println(outb.String())
-	// fmt.Println(errb.String())
-	// log.Debug().Str("stdout", outb.String()).Str("stderr", errb.String()).Msg("output from gitleaks")
-
-	// load the result into a GitleaksResult
-	resultfile, err := os.ReadFile(outputpath)
+	// sync (clone-or-update) the cached bare mirror, then carve a worktree
+	// out of it for gitleaks to scan
+	bare, unlock, err := cache.sync(ctx, orgname, repo.Name, repo.CloneURL)
 	if err != nil {
-		log.Error().Err(err).Str("repo", *repo.Name).Msg("error opening results file")
+		log.Error().Err(err).Str("repo", repo.Name).Msg("failed to sync cached bare mirror")
 		result.Err = err
 		results <- result
 		return
 	}
-	jsonResults := make([]GitleaksResult, 0)
-	err = json.Unmarshal(resultfile, &jsonResults)
-	if err != nil {
-		log.Error().Err(err).Str("repo", *repo.Name).Msg("error unmarshaling gitleaks results")
+	defer unlock()
+	if err := cache.worktree(ctx, bare, dir); err != nil {
+		log.Error().Err(err).Str("repo", repo.Name).Msg("failed to create worktree from cached mirror")
 		result.Err = err
 		results <- result
 		return
 	}
+	defer cache.removeWorktree(ctx, bare, dir)
+	// run each configured scanner backend and merge their findings
+	opts := ScanOptions{SinceCommit: state.lastSHA(orgname, repo.Name), Full: full}
+	allFindings := make([]Finding, 0)
+	for _, scanner := range scanners {
+		findings, err := scanner.Scan(ctx, dir, opts)
+		if err != nil {
+			log.Error().Err(err).Str("repo", repo.Name).Str("scanner", scanner.Name()).Msg("error running scanner on the repo")
+			result.Err = err
+			results <- result
+			return
+		}
+		allFindings = append(allFindings, findings...)
+	}
 	//success: return
-	result.Results = jsonResults
+	result.Results = dedupeFindings(allFindings)
 	result.Err = nil
+	if headSHA, err := headCommit(ctx, dir); err != nil {
+		log.Warn().Err(err).Str("repo", repo.Name).Msg("failed to resolve HEAD after scan, state not updated")
+	} else if err := state.setLastSHA(orgname, repo.Name, headSHA); err != nil {
+		log.Warn().Err(err).Str("repo", repo.Name).Msg("failed to persist scan state")
+	}
 	results <- result
 }
 
-func get_org_repos(orgname, pat string, daysago int) ([]*github.Repository, error) {
-	ctx := context.Background()
+// headCommit returns the commit SHA that HEAD points to in the given
+// worktree, so it can be recorded as the last scanned commit on success.
+func headCommit(ctx context.Context, dir string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", "-C", dir, "rev-parse", "HEAD")
+	out, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// newGithubClient builds a go-github client whose underlying transport
+// respects GitHub's rate limit and abuse-detection headers.
+func newGithubClient(ctx context.Context, pat string) *github.Client {
 	ts := oauth2.StaticTokenSource(
 		&oauth2.Token{AccessToken: pat},
 	)
 	tc := oauth2.NewClient(ctx, ts)
-	client := github.NewClient(tc)
-	//
-	time_ago := time.Now().AddDate(0, 0, (-1 * daysago))
-	org_repos := make([]*github.Repository, 0)
-	page := 0
-	for {
-		opt := &github.RepositoryListByOrgOptions{Type: "all", Sort: "pushed", Direction: "desc", ListOptions: github.ListOptions{Page: page}}
-		repos, _, err := client.Repositories.ListByOrg(context.Background(), orgname, opt)
-		if err != nil {
-			log.Error().Err(err).Str("org", orgname).Msg("Error getting repositories from Github")
-			return nil, err
-		}
-		saw_older := false
-		for _, repo := range repos {
-			if *repo.Archived {
-				continue
-			}
-			if repo.PushedAt.Time.Before(time_ago) {
-				saw_older = true
-				break
-			}
-			org_repos = append(org_repos, repo)
-		}
-		if saw_older {
-			break
-		}
-		page = page + 1
-	}
-	return org_repos, nil
+	tc.Transport = newRateLimitedTransport(tc.Transport)
+	return github.NewClient(tc)
 }
 
 func main() {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
 	// setup logging
 	zerolog.TimeFieldFormat = zerolog.TimeFormatUnix
 	log.Info().Msg("logging setup")
@@ -164,7 +134,22 @@ func main() {
 		gitleaks_toml_path = "./configs/gitleaks.toml"
 	}
 	check_gitleaks_conf(gitleaks_toml_path)
-	// check the PAT exists for each org
+	cache := newRepoCache(&conf)
+	state, err := loadScanState(stateDir())
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to load scan state")
+	}
+	findings_seen, err := loadFindingStore(stateDir())
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to load finding store")
+	}
+	reporters, err := reportersFor(&conf)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to build output sinks")
+	}
+	full_rescan := os.Getenv("MOSS_FULL") != ""
+	// check the PAT exists for each GitHub org (Gitlab/Gerrit sources carry
+	// their own token env names in their instance config instead)
 	pats := make(map[string]string, 0)
 	for _, org := range conf.GithubConfig.OrgsToScan {
 		patenv := fmt.Sprintf("PAT_%s", org)
@@ -175,38 +160,122 @@ func main() {
 		}
 		pats[org] = pat
 	}
+	// every org/group/host moss is configured to look at, github or not
+	all_orgs := make([]string, 0, len(conf.GithubConfig.OrgsToScan))
+	all_orgs = append(all_orgs, conf.GithubConfig.OrgsToScan...)
+	for org := range conf.GitlabConfig.Instances {
+		all_orgs = append(all_orgs, org)
+	}
+	for org := range conf.GerritConfig.Instances {
+		all_orgs = append(all_orgs, org)
+	}
 	// foreach org, get the repos according to days_to_scan
-	all_repos := make([]*github.Repository, 0)
-	for org, pat := range pats {
-		repos, err := get_org_repos(org, pat, conf.GithubConfig.DaysToScan)
+	type orgRepo struct {
+		org  string
+		repo Repo
+	}
+	all_repos := make([]orgRepo, 0)
+	// listOK tracks which orgs had a successful ListRepos call, so gc below
+	// can tell "org listed successfully with zero repos" apart from
+	// "listing failed" - an org missing from this map must not be read as
+	// "every repo for it was removed upstream".
+	listOK := make(map[string]bool, len(all_orgs))
+	for _, org := range all_orgs {
+		source, err := newRepoSource(&conf, org, pats[org])
+		if err != nil {
+			log.Error().Err(err).Str("org", org).Msg("Failed to build repo source. Continuing")
+			continue
+		}
+		time_ago := time.Now().AddDate(0, 0, (-1 * conf.daysToScanFor(org)))
+		repos, err := source.ListRepos(ctx, time_ago)
 		if err != nil {
 			log.Error().Err(err).Str("org", org).Msg("Failed to get repos from org. Continuing")
 			continue
 		}
-		all_repos = append(all_repos, repos...)
+		listOK[org] = true
+		for _, repo := range repos {
+			all_repos = append(all_repos, orgRepo{org: org, repo: repo})
+		}
 	}
-	// create the channel and kick off the scans
-	results := make(chan GitleaksRepoResult)
-	for _, repo := range all_repos {
-		reponame := repo.GetFullName()
-		orgname := strings.Split(reponame, "/")[0]
-		pat := pats[orgname]
-		go scan_repo(repo, pat, orgname, gitleaks_toml_path, results)
+	// in gc mode, just prune cache entries for repos no longer seen upstream
+	// and exit without scanning anything
+	if os.Getenv("MOSS_GC") != "" {
+		live := make(map[string]map[string]bool)
+		for _, or := range all_repos {
+			if live[or.org] == nil {
+				live[or.org] = make(map[string]bool)
+			}
+			live[or.org][or.repo.Name] = true
+		}
+		for _, org := range all_orgs {
+			if !listOK[org] {
+				log.Warn().Str("org", org).Msg("skipping gc for org whose repo listing failed this run")
+				continue
+			}
+			if err := cache.gc(org, live[org]); err != nil {
+				log.Error().Err(err).Str("org", org).Msg("failed to gc cache")
+			}
+		}
+		return
 	}
+	// feed the repos through a bounded worker pool instead of spawning one
+	// goroutine per repo, so a large org can't fan out uncontrollably
+	results := make(chan GitleaksRepoResult, len(all_repos))
+	jobs := make(chan func(context.Context), len(all_repos))
+	for _, or := range all_repos {
+		or := or
+		scanners, err := scannersFor(&conf, or.org, gitleaks_toml_path)
+		if err != nil {
+			log.Error().Err(err).Str("org", or.org).Msg("failed to build scanners for org. Skipping repo")
+			continue
+		}
+		jobs <- func(ctx context.Context) {
+			scan_repo(ctx, cache, state, full_rescan, scanners, or.repo, or.org, results)
+		}
+	}
+	close(jobs)
+	runWorkerPool(ctx, conf.maxConcurrent(), jobs)
 	// collect the results
-	collected := 0
-	final_results := make([]GitleaksRepoResult, 0)
-	for {
-		repoResult := <-results
+	close(results)
+	final_results := make([]GitleaksRepoResult, 0, len(all_repos))
+	for repoResult := range results {
 		final_results = append(final_results, repoResult)
-		collected = collected + 1
-		if collected >= len(all_repos) {
-			break
+	}
+	// optionally check whether each finding's secret is still live; off by
+	// default since it makes network calls against the secret's service
+	if conf.Verify.Enabled {
+		for i := range final_results {
+			verifyFindings(ctx, final_results[i].Results)
+		}
+	} else {
+		for i := range final_results {
+			for j := range final_results[i].Results {
+				final_results[i].Results[j].Verified = verifiedUnknown
+			}
+		}
+	}
+	// push only the findings sinks haven't already seen to every
+	// configured sink, then record them as seen
+	for _, repoResult := range final_results {
+		if repoResult.Err != nil || len(repoResult.Results) == 0 {
+			continue
+		}
+		fresh := findings_seen.newFindings(repoResult.Org, repoResult.Repository, repoResult.Results)
+		if len(fresh) == 0 {
+			continue
+		}
+		for _, reporter := range reporters {
+			if err := reporter.Report(ctx, repoResult, fresh); err != nil {
+				log.Error().Err(err).Str("repo", repoResult.Repository).Str("sink", reporter.Name()).Msg("failed to report findings")
+			}
+		}
+		if err := findings_seen.markSeen(repoResult.Org, repoResult.Repository, fresh); err != nil {
+			log.Error().Err(err).Str("repo", repoResult.Repository).Msg("failed to persist finding store")
 		}
 	}
 	// format and output the results nicely
 	if conf.Output.Format == "json" {
-		output := json_output(final_results, conf.GithubConfig.OrgsToScan)
+		output := json_output(final_results, all_orgs)
 		// todo: make this part of the conf
 		os.WriteFile("./output.json", []byte(output), 0644)
 		fmt.Println(output)
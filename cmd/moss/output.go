@@ -0,0 +1,19 @@
+package main
+
+import "encoding/json"
+
+// jsonOutput is the shape written to ./output.json.
+type jsonOutput struct {
+	Orgs  []string             `json:"orgs"`
+	Repos []GitleaksRepoResult `json:"repos"`
+}
+
+// json_output renders the collected per-repo results as indented JSON.
+func json_output(results []GitleaksRepoResult, orgs []string) string {
+	out := jsonOutput{Orgs: orgs, Repos: results}
+	b, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return "{}"
+	}
+	return string(b)
+}
@@ -0,0 +1,32 @@
+package main
+
+import "context"
+
+// runWorkerPool drives `jobs` through up to `workers` concurrent scan_repo
+// calls, instead of spawning one goroutine per repo unconditionally.
+func runWorkerPool(ctx context.Context, workers int, jobs <-chan func(context.Context)) {
+	if workers <= 0 {
+		workers = 1
+	}
+	done := make(chan struct{})
+	for i := 0; i < workers; i++ {
+		go func() {
+			for {
+				select {
+				case <-ctx.Done():
+					done <- struct{}{}
+					return
+				case job, ok := <-jobs:
+					if !ok {
+						done <- struct{}{}
+						return
+					}
+					job(ctx)
+				}
+			}
+		}()
+	}
+	for i := 0; i < workers; i++ {
+		<-done
+	}
+}
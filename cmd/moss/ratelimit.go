@@ -0,0 +1,85 @@
+package main
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// rateLimitedTransport wraps an http.RoundTripper so GitHub API calls back
+// off when the remaining rate limit budget is exhausted, honor Retry-After on
+// abuse responses, and retry secondary rate limit / abuse errors (403) with
+// exponential backoff instead of hammering the API.
+type rateLimitedTransport struct {
+	base    http.RoundTripper
+	retries int
+}
+
+func newRateLimitedTransport(base http.RoundTripper) *rateLimitedTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &rateLimitedTransport{base: base, retries: 5}
+}
+
+func (t *rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var resp *http.Response
+	var err error
+	for attempt := 0; attempt <= t.retries; attempt++ {
+		resp, err = t.base.RoundTrip(req)
+		if err != nil {
+			return resp, err
+		}
+		if remaining := resp.Header.Get("X-RateLimit-Remaining"); remaining != "" {
+			if n, convErr := strconv.Atoi(remaining); convErr == nil && n == 0 {
+				sleepUntilReset(resp.Header.Get("X-RateLimit-Reset"))
+				resp.Body.Close()
+				continue
+			}
+		}
+		if resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests {
+			wait := retryAfter(resp.Header.Get("Retry-After"))
+			if wait == 0 {
+				wait = time.Duration(math.Pow(2, float64(attempt))) * time.Second
+			}
+			log.Warn().Int("status", resp.StatusCode).Dur("wait", wait).Str("url", req.URL.String()).Msg("github rate/abuse limited, backing off")
+			resp.Body.Close()
+			time.Sleep(wait)
+			continue
+		}
+		return resp, nil
+	}
+	return resp, err
+}
+
+func sleepUntilReset(resetHeader string) {
+	if resetHeader == "" {
+		time.Sleep(time.Minute)
+		return
+	}
+	epoch, err := strconv.ParseInt(resetHeader, 10, 64)
+	if err != nil {
+		time.Sleep(time.Minute)
+		return
+	}
+	wait := time.Until(time.Unix(epoch, 0))
+	if wait < 0 {
+		wait = 0
+	}
+	log.Warn().Dur("wait", wait).Msg("github rate limit exhausted, sleeping until reset")
+	time.Sleep(wait)
+}
+
+func retryAfter(header string) time.Duration {
+	if header == "" {
+		return 0
+	}
+	secs, err := strconv.Atoi(header)
+	if err != nil {
+		return 0
+	}
+	return time.Duration(secs) * time.Second
+}
@@ -0,0 +1,11 @@
+package main
+
+// Repo is the source-agnostic shape scan_repo and reporting work with, so
+// they don't need to know whether a repo came from GitHub, GitLab, or
+// Gerrit.
+type Repo struct {
+	Name          string
+	CloneURL      string
+	Private       bool
+	DefaultBranch string
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"fmt"
+)
+
+// Reporter is a sink that findings get pushed to, in addition to the local
+// JSON output file. Report is only ever called with findings that haven't
+// been sent to this sink before (see findingStore).
+type Reporter interface {
+	Name() string
+	Report(ctx context.Context, repo GitleaksRepoResult, newFindings []Finding) error
+}
+
+// SinkConfig configures one entry in Conf.Output.Sinks.
+type SinkConfig struct {
+	Type string `yaml:"type"` // "webhook", "s3", "github_issues"
+
+	// webhook
+	WebhookURL string `yaml:"webhook_url"`
+
+	// s3 / gcs
+	Bucket string `yaml:"bucket"`
+	Prefix string `yaml:"prefix"`
+
+	// github_issues
+	TokenEnv string `yaml:"token_env"`
+}
+
+// newReporter builds the Reporter for one sink config entry.
+func newReporter(cfg SinkConfig) (Reporter, error) {
+	switch cfg.Type {
+	case "webhook":
+		if cfg.WebhookURL == "" {
+			return nil, fmt.Errorf("webhook sink missing webhook_url")
+		}
+		return &webhookReporter{url: cfg.WebhookURL}, nil
+	case "s3":
+		if cfg.Bucket == "" {
+			return nil, fmt.Errorf("s3 sink missing bucket")
+		}
+		return newS3Reporter(cfg.Bucket, cfg.Prefix), nil
+	case "github_issues":
+		if cfg.TokenEnv == "" {
+			return nil, fmt.Errorf("github_issues sink missing token_env")
+		}
+		return &githubIssuesReporter{tokenEnv: cfg.TokenEnv}, nil
+	default:
+		return nil, fmt.Errorf("unknown sink type %q", cfg.Type)
+	}
+}
+
+// reportersFor builds every sink configured in Conf.Output.Sinks.
+func reportersFor(conf *Conf) ([]Reporter, error) {
+	reporters := make([]Reporter, 0, len(conf.Output.Sinks))
+	for _, sink := range conf.Output.Sinks {
+		reporter, err := newReporter(sink)
+		if err != nil {
+			return nil, err
+		}
+		reporters = append(reporters, reporter)
+	}
+	return reporters, nil
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/google/go-github/v47/github"
+)
+
+// githubIssuesReporter opens (or comments on) a GitHub issue on the
+// offending repo for each run's new findings, tagged so re-detections of
+// an already-filed secret don't open a duplicate issue.
+type githubIssuesReporter struct {
+	tokenEnv string
+}
+
+const githubIssuesMarker = "<!-- moss:secret-finding -->"
+
+func (r *githubIssuesReporter) Name() string { return "github_issues" }
+
+func (r *githubIssuesReporter) Report(ctx context.Context, repo GitleaksRepoResult, newFindings []Finding) error {
+	if len(newFindings) == 0 {
+		return nil
+	}
+	token := os.Getenv(r.tokenEnv)
+	if token == "" {
+		return fmt.Errorf("github issues token env %q is empty", r.tokenEnv)
+	}
+	client := newGithubClient(ctx, token)
+
+	title := fmt.Sprintf("moss: %d secret(s) detected in %s", len(newFindings), repo.Repository)
+	body := githubIssuesMarker + "\n\nmoss found new potential secrets:\n"
+	for _, f := range newFindings {
+		body += fmt.Sprintf("\n- `%s` — %s:%d (%s)", f.Tool, f.File, f.Line, f.RuleID)
+	}
+
+	existing, err := r.findOpenIssue(ctx, client, repo.Org, repo.Repository)
+	if err != nil {
+		return fmt.Errorf("searching for existing moss issue: %w", err)
+	}
+	if existing != nil {
+		_, _, err := client.Issues.CreateComment(ctx, repo.Org, repo.Repository, existing.GetNumber(), &github.IssueComment{Body: &body})
+		if err != nil {
+			return fmt.Errorf("commenting on existing moss issue: %w", err)
+		}
+		return nil
+	}
+
+	_, _, err = client.Issues.Create(ctx, repo.Org, repo.Repository, &github.IssueRequest{
+		Title: &title,
+		Body:  &body,
+	})
+	if err != nil {
+		return fmt.Errorf("creating moss issue: %w", err)
+	}
+	return nil
+}
+
+// findOpenIssue looks for an already-open moss-tagged issue on the repo so
+// new findings get appended to it instead of spawning a duplicate.
+func (r *githubIssuesReporter) findOpenIssue(ctx context.Context, client *github.Client, org, repo string) (*github.Issue, error) {
+	opt := &github.IssueListByRepoOptions{State: "open", ListOptions: github.ListOptions{Page: 1}}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+		}
+		issues, resp, err := client.Issues.ListByRepo(ctx, org, repo, opt)
+		if err != nil {
+			return nil, err
+		}
+		for _, issue := range issues {
+			if strings.Contains(issue.GetBody(), githubIssuesMarker) {
+				return issue, nil
+			}
+		}
+		if resp.NextPage == 0 {
+			return nil, nil
+		}
+		opt.Page = resp.NextPage
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// s3Reporter uploads the new findings for a repo as a dated JSON object in
+// an S3 (or S3-compatible GCS) bucket.
+type s3Reporter struct {
+	bucket string
+	prefix string
+}
+
+func newS3Reporter(bucket, prefix string) *s3Reporter {
+	return &s3Reporter{bucket: bucket, prefix: prefix}
+}
+
+func (r *s3Reporter) Name() string { return "s3" }
+
+func (r *s3Reporter) Report(ctx context.Context, repo GitleaksRepoResult, newFindings []Finding) error {
+	if len(newFindings) == 0 {
+		return nil
+	}
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return fmt.Errorf("loading aws config: %w", err)
+	}
+	client := s3.NewFromConfig(cfg)
+
+	body, err := json.MarshalIndent(newFindings, "", "  ")
+	if err != nil {
+		return err
+	}
+	key := fmt.Sprintf("%s%s/%s/%s.json", r.prefix, repo.Org, repo.Repository, time.Now().UTC().Format("2006-01-02T15-04-05"))
+	_, err = client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(r.bucket),
+		Key:    aws.String(key),
+		Body:   bytes.NewReader(body),
+	})
+	if err != nil {
+		return fmt.Errorf("uploading findings to s3: %w", err)
+	}
+	return nil
+}
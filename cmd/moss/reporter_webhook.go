@@ -0,0 +1,46 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// webhookReporter posts new findings as a message to a Slack incoming
+// webhook (or any other endpoint that accepts a Slack-shaped {"text": ...}
+// payload).
+type webhookReporter struct {
+	url string
+}
+
+func (r *webhookReporter) Name() string { return "webhook" }
+
+func (r *webhookReporter) Report(ctx context.Context, repo GitleaksRepoResult, newFindings []Finding) error {
+	if len(newFindings) == 0 {
+		return nil
+	}
+	text := fmt.Sprintf("found %d new secret(s) in %s/%s", len(newFindings), repo.Org, repo.Repository)
+	for _, f := range newFindings {
+		text += fmt.Sprintf("\n- [%s] %s:%d (%s)", f.Tool, f.File, f.Line, f.RuleID)
+	}
+	payload, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("posting to webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// RepoSource enumerates the repos belonging to one org/group/project that
+// have changed since the given time. Implementations wrap a specific code
+// host (GitHub, GitLab, Gerrit, ...).
+type RepoSource interface {
+	ListRepos(ctx context.Context, since time.Time) ([]Repo, error)
+}
+
+// newRepoSource builds the RepoSource for a configured org, dispatching on
+// which section of Conf claims it.
+func newRepoSource(conf *Conf, org, pat string) (RepoSource, error) {
+	if gl, ok := conf.GitlabConfig.instance(org); ok {
+		return newGitlabSource(gl, org), nil
+	}
+	if gr, ok := conf.GerritConfig.instance(org); ok {
+		return newGerritSource(gr), nil
+	}
+	if pat == "" {
+		return nil, fmt.Errorf("no PAT and no gitlab/gerrit config found for %q", org)
+	}
+	return &githubSource{org: org, pat: pat}, nil
+}
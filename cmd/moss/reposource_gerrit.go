@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/build/gerrit"
+)
+
+// gerritSource lists every non-hidden project on a Gerrit host. The since
+// argument to ListRepos is not applied.
+type gerritSource struct {
+	cfg GerritInstance
+}
+
+func newGerritSource(cfg GerritInstance) *gerritSource {
+	return &gerritSource{cfg: cfg}
+}
+
+func (s *gerritSource) ListRepos(ctx context.Context, since time.Time) ([]Repo, error) {
+	token := os.Getenv(s.cfg.TokenEnv)
+	if token == "" {
+		return nil, fmt.Errorf("gerrit token env %q is empty", s.cfg.TokenEnv)
+	}
+	client := gerrit.NewClient(s.cfg.BaseURL, gerrit.BasicAuth("moss", token))
+
+	projects, err := client.ListProjects(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing gerrit projects at %s: %w", s.cfg.BaseURL, err)
+	}
+
+	repos := make([]Repo, 0, len(projects))
+	for _, project := range projects {
+		if project.State == "HIDDEN" {
+			continue
+		}
+		cloneUrl := strings.TrimSuffix(s.cfg.BaseURL, "/") + "/a/" + project.Name
+		cloneUrl = strings.Replace(cloneUrl, "https://", fmt.Sprintf("https://moss:%s@", token), 1)
+		repos = append(repos, Repo{
+			Name:     project.Name,
+			CloneURL: cloneUrl,
+		})
+	}
+	// Gerrit's project list has no per-project pushed-at timestamp, so we
+	// can't filter by `since` here; callers relying on the incremental
+	// commit-SHA state store (scanState) still skip unchanged history.
+	_ = since
+	return repos, nil
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v47/github"
+	"github.com/rs/zerolog/log"
+)
+
+// githubSource lists non-archived, recently-pushed repos for a GitHub org.
+type githubSource struct {
+	org string
+	pat string
+}
+
+func (s *githubSource) ListRepos(ctx context.Context, since time.Time) ([]Repo, error) {
+	client := newGithubClient(ctx, s.pat)
+	return listGithubRepos(ctx, client, s.org, s.pat, since)
+}
+
+// listGithubRepos pages through an org's repos via client, newest-pushed
+// first, stopping once it sees a repo pushed before since. Split out from
+// ListRepos so the pagination loop can be exercised against a fake GitHub
+// server without a real token.
+func listGithubRepos(ctx context.Context, client *github.Client, org, pat string, since time.Time) ([]Repo, error) {
+	repos := make([]Repo, 0)
+	// go-github pages are 1-indexed; starting at 0 silently re-fetched
+	// page 1 twice and never moved past it.
+	page := 1
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+		}
+		opt := &github.RepositoryListByOrgOptions{Type: "all", Sort: "pushed", Direction: "desc", ListOptions: github.ListOptions{Page: page}}
+		ghRepos, resp, err := client.Repositories.ListByOrg(ctx, org, opt)
+		if err != nil {
+			log.Error().Err(err).Str("org", org).Msg("Error getting repositories from Github")
+			return nil, err
+		}
+		saw_older := false
+		for _, repo := range ghRepos {
+			if repo.GetArchived() {
+				continue
+			}
+			if repo.GetPushedAt().Time.Before(since) {
+				saw_older = true
+				break
+			}
+			cloneUrl := repo.GetCloneURL()
+			cloneUrl = strings.Replace(cloneUrl, "https://", "https://"+pat+"@", 1)
+			repos = append(repos, Repo{
+				Name:          repo.GetName(),
+				CloneURL:      cloneUrl,
+				Private:       repo.GetPrivate(),
+				DefaultBranch: repo.GetDefaultBranch(),
+			})
+		}
+		if saw_older || resp.NextPage == 0 {
+			break
+		}
+		page = resp.NextPage
+	}
+	return repos, nil
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v47/github"
+)
+
+// TestListGithubReposPaginates serves three pages of org repos from a fake
+// GitHub API and checks that listGithubRepos follows Link headers to fetch
+// all of them, rather than re-fetching page 1 forever or stopping after one
+// page.
+func TestListGithubReposPaginates(t *testing.T) {
+	const perPage = 2
+	names := []string{"repo-a", "repo-b", "repo-c", "repo-d", "repo-e"}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/orgs/acme/repos", func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		if page == "" {
+			page = "1"
+		}
+		var start int
+		fmt.Sscanf(page, "%d", &start)
+		start = (start - 1) * perPage
+		end := start + perPage
+		if end > len(names) {
+			end = len(names)
+		}
+
+		if end < len(names) {
+			nextPage := end/perPage + 1
+			w.Header().Set("Link", fmt.Sprintf(`<http://%s/orgs/acme/repos?page=%d>; rel="next"`, r.Host, nextPage))
+		}
+
+		fmt.Fprint(w, "[")
+		for i := start; i < end; i++ {
+			if i > start {
+				fmt.Fprint(w, ",")
+			}
+			fmt.Fprintf(w, `{"name":%q,"clone_url":"https://github.com/acme/%s.git","pushed_at":"2026-01-01T00:00:00Z"}`, names[i], names[i])
+		}
+		fmt.Fprint(w, "]")
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := github.NewClient(nil)
+	baseURL, err := client.BaseURL.Parse(server.URL + "/")
+	if err != nil {
+		t.Fatalf("parsing base URL: %v", err)
+	}
+	client.BaseURL = baseURL
+
+	repos, err := listGithubRepos(context.Background(), client, "acme", "tok", time.Time{})
+	if err != nil {
+		t.Fatalf("listGithubRepos: %v", err)
+	}
+	if len(repos) != len(names) {
+		t.Fatalf("got %d repos, want %d: %v", len(repos), len(names), repos)
+	}
+	for i, name := range names {
+		if repos[i].Name != name {
+			t.Errorf("repos[%d].Name = %q, want %q", i, repos[i].Name, name)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/xanzy/go-gitlab"
+)
+
+// gitlabSource lists recently-active projects under a GitLab group
+// (including its subgroups).
+type gitlabSource struct {
+	cfg GitlabInstance
+	org string
+}
+
+func newGitlabSource(cfg GitlabInstance, org string) *gitlabSource {
+	return &gitlabSource{cfg: cfg, org: org}
+}
+
+func (s *gitlabSource) ListRepos(ctx context.Context, since time.Time) ([]Repo, error) {
+	token := os.Getenv(s.cfg.TokenEnv)
+	if token == "" {
+		return nil, fmt.Errorf("gitlab token env %q is empty for %q", s.cfg.TokenEnv, s.org)
+	}
+	client, err := gitlab.NewClient(token, gitlab.WithBaseURL(s.cfg.BaseURL))
+	if err != nil {
+		return nil, fmt.Errorf("building gitlab client for %q: %w", s.org, err)
+	}
+
+	repos := make([]Repo, 0)
+	opt := &gitlab.ListGroupProjectsOptions{
+		ListOptions:      gitlab.ListOptions{PerPage: 100},
+		IncludeSubGroups: gitlab.Bool(true),
+		OrderBy:          gitlab.String("last_activity_at"),
+		Sort:             gitlab.String("desc"),
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+		}
+		projects, resp, err := client.Groups.ListGroupProjects(s.cfg.Group, opt, gitlab.WithContext(ctx))
+		if err != nil {
+			log.Error().Err(err).Str("group", s.cfg.Group).Msg("Error getting projects from Gitlab")
+			return nil, err
+		}
+		saw_older := false
+		for _, project := range projects {
+			if project.Archived {
+				continue
+			}
+			if project.LastActivityAt != nil && project.LastActivityAt.Before(since) {
+				saw_older = true
+				break
+			}
+			cloneUrl := strings.Replace(project.HTTPURLToRepo, "https://", fmt.Sprintf("https://oauth2:%s@", token), 1)
+			repos = append(repos, Repo{
+				Name:          project.Path,
+				CloneURL:      cloneUrl,
+				Private:       project.Visibility == gitlab.PrivateVisibility,
+				DefaultBranch: project.DefaultBranch,
+			})
+		}
+		if saw_older || resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	return repos, nil
+}
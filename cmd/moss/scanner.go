@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// ScanOptions carries the incremental-scan knobs that apply regardless of
+// which backend does the scanning.
+type ScanOptions struct {
+	// SinceCommit, when set, restricts the scan to history after this SHA.
+	SinceCommit string
+	// Full forces a from-scratch scan even if SinceCommit is known.
+	Full bool
+}
+
+// Scanner is a secret-scanning backend. Implementations shell out to (or
+// embed) a scanning engine and normalize its output to []Finding.
+type Scanner interface {
+	Name() string
+	Scan(ctx context.Context, repoDir string, opts ScanOptions) ([]Finding, error)
+}
+
+// newScanner builds the Scanner for the given backend name.
+func newScanner(backend, glConfPath string) (Scanner, error) {
+	switch backend {
+	case "", "gitleaks":
+		return &gitleaksScanner{confPath: glConfPath}, nil
+	case "trufflehog":
+		return &trufflehogScanner{}, nil
+	default:
+		return nil, fmt.Errorf("unknown scanner backend %q", backend)
+	}
+}
+
+// scannersFor builds the Scanner set configured for org.
+func scannersFor(conf *Conf, org, glConfPath string) ([]Scanner, error) {
+	backends := conf.backendsFor(org)
+	scanners := make([]Scanner, 0, len(backends))
+	for _, backend := range backends {
+		scanner, err := newScanner(backend, glConfPath)
+		if err != nil {
+			return nil, err
+		}
+		scanners = append(scanners, scanner)
+	}
+	return scanners, nil
+}
+
+// secretHash fingerprints a secret value so findings from different
+// backends (or re-detections of the same secret) can be deduped/merged.
+func secretHash(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// dedupeFindings merges findings from one or more backends, keyed by
+// (file, line, secret hash), keeping the first occurrence of each.
+func dedupeFindings(findings []Finding) []Finding {
+	seen := make(map[string]bool, len(findings))
+	out := make([]Finding, 0, len(findings))
+	for _, f := range findings {
+		key := fmt.Sprintf("%s:%d:%s", f.File, f.Line, f.SecretHash)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		out = append(out, f)
+	}
+	return out
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+// gitleaksResult mirrors a single finding entry from gitleaks' JSON report.
+type gitleaksResult struct {
+	Description string `json:"Description"`
+	StartLine   int    `json:"StartLine"`
+	EndLine     int    `json:"EndLine"`
+	File        string `json:"File"`
+	Commit      string `json:"Commit"`
+	Secret      string `json:"Secret"`
+	Match       string `json:"Match"`
+	RuleID      string `json:"RuleID"`
+	Author      string `json:"Author"`
+	Email       string `json:"Email"`
+	Date        string `json:"Date"`
+}
+
+// gitleaksScanner shells out to the gitleaks CLI.
+type gitleaksScanner struct {
+	confPath string
+}
+
+func (s *gitleaksScanner) Name() string { return "gitleaks" }
+
+func (s *gitleaksScanner) Scan(ctx context.Context, repoDir string, opts ScanOptions) ([]Finding, error) {
+	outputpath := fmt.Sprintf("%s/__gitleaks.json", repoDir)
+	outputarg := fmt.Sprintf("-r=%s", outputpath)
+	confpath := fmt.Sprintf("-c=%s", s.confPath)
+
+	var args []string
+	if !opts.Full && opts.SinceCommit != "" {
+		// scan only the commits added since the last successful run
+		logopts := fmt.Sprintf("--log-opts=%s..HEAD", opts.SinceCommit)
+		args = []string{"detect", "-v", "-f=json", "--exit-code=0", outputarg, confpath, logopts, repoDir}
+	} else {
+		// not exactly sure why gitleaks doesn't detect that
+		// it IS a git repo, but we can still detect secrets
+		args = []string{"detect", "-v", "--no-git", "-f=json", "--exit-code=0", outputarg, confpath, repoDir}
+	}
+
+	var outb, errb bytes.Buffer
+	cmd := exec.CommandContext(ctx, "gitleaks", args...)
+	cmd.Stdout = &outb
+	cmd.Stderr = &errb
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("running gitleaks: %w", err)
+	}
+
+	resultfile, err := os.ReadFile(outputpath)
+	if err != nil {
+		return nil, fmt.Errorf("opening gitleaks results: %w", err)
+	}
+	raw := make([]gitleaksResult, 0)
+	if err := json.Unmarshal(resultfile, &raw); err != nil {
+		return nil, fmt.Errorf("unmarshaling gitleaks results: %w", err)
+	}
+
+	findings := make([]Finding, 0, len(raw))
+	for _, r := range raw {
+		findings = append(findings, Finding{
+			Tool:        "gitleaks",
+			RuleID:      r.RuleID,
+			Description: r.Description,
+			File:        r.File,
+			Line:        r.StartLine,
+			Commit:      r.Commit,
+			Secret:      r.Secret,
+			SecretHash:  secretHash(r.Secret),
+			Author:      r.Author,
+			Email:       r.Email,
+			Date:        r.Date,
+		})
+	}
+	return findings, nil
+}
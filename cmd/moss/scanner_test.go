@@ -0,0 +1,26 @@
+package main
+
+import "testing"
+
+func TestDedupeFindings(t *testing.T) {
+	a := Finding{Tool: "gitleaks", File: "main.go", Line: 10, SecretHash: secretHash("s3cr3t")}
+	aAgain := Finding{Tool: "trufflehog", File: "main.go", Line: 10, SecretHash: secretHash("s3cr3t")}
+	diffLine := Finding{Tool: "gitleaks", File: "main.go", Line: 11, SecretHash: secretHash("s3cr3t")}
+	diffFile := Finding{Tool: "gitleaks", File: "other.go", Line: 10, SecretHash: secretHash("s3cr3t")}
+	diffSecret := Finding{Tool: "gitleaks", File: "main.go", Line: 10, SecretHash: secretHash("different")}
+
+	got := dedupeFindings([]Finding{a, aAgain, diffLine, diffFile, diffSecret})
+
+	if len(got) != 4 {
+		t.Fatalf("got %d findings, want 4: %+v", len(got), got)
+	}
+	if got[0].Tool != "gitleaks" {
+		t.Errorf("expected first occurrence (gitleaks) to be kept, got %q", got[0].Tool)
+	}
+}
+
+func TestDedupeFindingsEmpty(t *testing.T) {
+	if got := dedupeFindings(nil); len(got) != 0 {
+		t.Errorf("dedupeFindings(nil) = %+v, want empty", got)
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+)
+
+// trufflehogResult mirrors a single line of `trufflehog ... --json` output.
+type trufflehogResult struct {
+	DetectorName   string `json:"DetectorName"`
+	Raw            string `json:"Raw"`
+	Verified       bool   `json:"Verified"`
+	SourceMetadata struct {
+		Data struct {
+			Git struct {
+				Commit    string `json:"commit"`
+				File      string `json:"file"`
+				Line      int    `json:"line"`
+				Email     string `json:"email"`
+				Timestamp string `json:"timestamp"`
+			} `json:"Git"`
+		} `json:"Data"`
+	} `json:"SourceMetadata"`
+}
+
+// trufflehogScanner shells out to the trufflehog CLI against a local git
+// checkout.
+type trufflehogScanner struct{}
+
+func (s *trufflehogScanner) Name() string { return "trufflehog" }
+
+func (s *trufflehogScanner) Scan(ctx context.Context, repoDir string, opts ScanOptions) ([]Finding, error) {
+	source := fmt.Sprintf("file://%s", repoDir)
+	args := []string{"git", source, "--json", "--no-update"}
+	if !opts.Full && opts.SinceCommit != "" {
+		args = append(args, fmt.Sprintf("--since-commit=%s", opts.SinceCommit))
+	}
+
+	var outb, errb bytes.Buffer
+	cmd := exec.CommandContext(ctx, "trufflehog", args...)
+	cmd.Stdout = &outb
+	cmd.Stderr = &errb
+	// trufflehog exits non-zero when it finds verified secrets, so don't
+	// treat that alone as a hard failure - only bail if we got no output.
+	runErr := cmd.Run()
+	if runErr != nil && outb.Len() == 0 {
+		return nil, fmt.Errorf("running trufflehog: %w", runErr)
+	}
+
+	findings := make([]Finding, 0)
+	scanner := bufio.NewScanner(&outb)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var r trufflehogResult
+		if err := json.Unmarshal(line, &r); err != nil {
+			return nil, fmt.Errorf("unmarshaling trufflehog result: %w", err)
+		}
+		findings = append(findings, Finding{
+			Tool:        "trufflehog",
+			RuleID:      r.DetectorName,
+			Description: r.DetectorName,
+			File:        r.SourceMetadata.Data.Git.File,
+			Line:        r.SourceMetadata.Data.Git.Line,
+			Commit:      r.SourceMetadata.Data.Git.Commit,
+			Secret:      r.Raw,
+			SecretHash:  secretHash(r.Raw),
+			Email:       r.SourceMetadata.Data.Git.Email,
+			Date:        r.SourceMetadata.Data.Git.Timestamp,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading trufflehog output: %w", err)
+	}
+	return findings, nil
+}
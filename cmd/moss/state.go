@@ -0,0 +1,103 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/gofrs/flock"
+)
+
+// scanState tracks the last commit SHA successfully scanned for each
+// org/repo, so subsequent runs only need to look at new commits.
+type scanState struct {
+	path string
+	mu   sync.Mutex
+	data map[string]string // "org/repo" -> last scanned SHA
+}
+
+// stateDir returns the directory holding the scan state file, honoring
+// MOSS_STATEDIR and falling back to a sane default.
+func stateDir() string {
+	if dir := os.Getenv("MOSS_STATEDIR"); dir != "" {
+		return dir
+	}
+	return "./.moss-state"
+}
+
+// loadScanState reads the state file from dir, returning an empty state if
+// it doesn't exist yet.
+func loadScanState(dir string) (*scanState, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	s := &scanState{path: filepath.Join(dir, "state.json")}
+	data, err := readStateFile(s.path)
+	if err != nil {
+		return nil, err
+	}
+	s.data = data
+	return s, nil
+}
+
+// readStateFile reads the SHA map from path, returning an empty map if the
+// file doesn't exist yet or is empty.
+func readStateFile(path string) (map[string]string, error) {
+	data := make(map[string]string)
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return data, nil
+		}
+		return nil, err
+	}
+	if len(raw) == 0 {
+		return data, nil
+	}
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// lastSHA returns the last scanned commit SHA for org/repo, or "" if none
+// has been recorded yet.
+func (s *scanState) lastSHA(org, repo string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.data[org+"/"+repo]
+}
+
+// setLastSHA records the last scanned commit SHA for org/repo and persists
+// the state file. It reads the current on-disk contents while holding the
+// lock and merges into them rather than overwriting with this process's
+// in-memory copy, so two concurrent `moss` invocations (chunk0-1's cache
+// locks are per-repo, not global, so this does happen) don't stomp on each
+// other's updates to other repos.
+func (s *scanState) setLastSHA(org, repo, sha string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lock := flock.New(s.path + ".lock")
+	if err := lock.Lock(); err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	onDisk, err := readStateFile(s.path)
+	if err != nil {
+		return err
+	}
+	for k, v := range s.data {
+		onDisk[k] = v
+	}
+	onDisk[org+"/"+repo] = sha
+	s.data = onDisk
+
+	raw, err := json.MarshalIndent(s.data, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, raw, 0644)
+}
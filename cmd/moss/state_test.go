@@ -0,0 +1,39 @@
+package main
+
+import "testing"
+
+// TestSetLastSHAMergesConcurrentWrites simulates two scanState handles (as
+// two moss processes would have) both holding stale in-memory state and
+// writing for different repos. Neither process re-reads the state file in
+// between, so the fix must come from setLastSHA merging with what's on
+// disk, not from overwriting it with a single process's snapshot.
+func TestSetLastSHAMergesConcurrentWrites(t *testing.T) {
+	dir := t.TempDir()
+
+	a, err := loadScanState(dir)
+	if err != nil {
+		t.Fatalf("loadScanState a: %v", err)
+	}
+	b, err := loadScanState(dir)
+	if err != nil {
+		t.Fatalf("loadScanState b: %v", err)
+	}
+
+	if err := a.setLastSHA("org", "repo-a", "sha-a"); err != nil {
+		t.Fatalf("setLastSHA a: %v", err)
+	}
+	if err := b.setLastSHA("org", "repo-b", "sha-b"); err != nil {
+		t.Fatalf("setLastSHA b: %v", err)
+	}
+
+	final, err := loadScanState(dir)
+	if err != nil {
+		t.Fatalf("loadScanState final: %v", err)
+	}
+	if got := final.lastSHA("org", "repo-a"); got != "sha-a" {
+		t.Errorf("repo-a sha = %q, want %q", got, "sha-a")
+	}
+	if got := final.lastSHA("org", "repo-b"); got != "sha-b" {
+		t.Errorf("repo-b sha = %q, want %q", got, "sha-b")
+	}
+}
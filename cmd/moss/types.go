@@ -0,0 +1,30 @@
+package main
+
+// Finding is the normalized shape every Scanner backend produces, so
+// reporting and dedup don't need to know which engine found what.
+type Finding struct {
+	Tool        string
+	RuleID      string
+	Description string
+	File        string
+	Line        int
+	Commit      string
+	Secret      string
+	SecretHash  string
+	Author      string
+	Email       string
+	Date        string
+	// Verified is "true", "false", or "unknown" (the default, meaning the
+	// verification pass is disabled or has no Verifier for this RuleID).
+	Verified string
+}
+
+// GitleaksRepoResult is the per-repo outcome of a scan, successful or not.
+type GitleaksRepoResult struct {
+	Repository string
+	URL        string
+	IsPrivate  bool
+	Org        string
+	Results    []Finding
+	Err        error
+}
@@ -0,0 +1,48 @@
+package main
+
+import "context"
+
+const (
+	verifiedTrue    = "true"
+	verifiedFalse   = "false"
+	verifiedUnknown = "unknown"
+)
+
+// Verifier checks whether a single finding's secret is still live against
+// the service it belongs to.
+type Verifier interface {
+	Verify(ctx context.Context, f Finding) (bool, error)
+}
+
+// verifierRegistry maps a gitleaks/trufflehog RuleID to the Verifier that
+// knows how to check liveness for that kind of secret.
+var verifierRegistry = map[string]Verifier{
+	"aws-access-token":    awsVerifier{},
+	"github-pat":          githubPATVerifier{},
+	"slack-access-token":  slackVerifier{},
+	"gcp-service-account": gcpVerifier{},
+}
+
+// verifyFindings runs the liveness pass over findings in place, tagging
+// each with Verified = "true"/"false"/"unknown". It's only called when
+// Conf.Verify.Enabled, since it makes live network calls against the
+// services the secrets belong to.
+func verifyFindings(ctx context.Context, findings []Finding) {
+	for i, f := range findings {
+		verifier, ok := verifierRegistry[f.RuleID]
+		if !ok {
+			findings[i].Verified = verifiedUnknown
+			continue
+		}
+		live, err := verifier.Verify(ctx, f)
+		if err != nil {
+			findings[i].Verified = verifiedUnknown
+			continue
+		}
+		if live {
+			findings[i].Verified = verifiedTrue
+		} else {
+			findings[i].Verified = verifiedFalse
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"context"
+
+	"golang.org/x/oauth2/google"
+)
+
+// gcpVerifier checks a GCP service account JSON key by exchanging it for
+// an OAuth2 token - the exchange only succeeds if the key is still active.
+type gcpVerifier struct{}
+
+func (gcpVerifier) Verify(ctx context.Context, f Finding) (bool, error) {
+	creds, err := google.CredentialsFromJSON(ctx, []byte(f.Secret), "https://www.googleapis.com/auth/cloud-platform")
+	if err != nil {
+		return false, nil
+	}
+	if _, err := creds.TokenSource.Token(); err != nil {
+		return false, nil
+	}
+	return true, nil
+}
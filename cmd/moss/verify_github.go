@@ -0,0 +1,16 @@
+package main
+
+import "context"
+
+// githubPATVerifier checks a GitHub personal access token by calling
+// GET /user - a 200 means it's still live.
+type githubPATVerifier struct{}
+
+func (githubPATVerifier) Verify(ctx context.Context, f Finding) (bool, error) {
+	client := newGithubClient(ctx, f.Secret)
+	_, _, err := client.Users.Get(ctx, "")
+	if err != nil {
+		return false, nil
+	}
+	return true, nil
+}